@@ -0,0 +1,37 @@
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/command/views"
+	"github.com/opentofu/opentofu/internal/backend"
+)
+
+// applyPlan carries out op.Plan through b, without requiring it to first be
+// written to and re-read from a plan file. It re-acquires b's state lock
+// for the duration of the apply, since the lock taken for the plan that
+// produced op.Plan is released as soon as that plan finishes.
+//
+// PlanCommand uses this for its "-auto-apply" handoff. It's written against
+// the backend.Backend interface, rather than against *local.Local or
+// terraform.Context directly, so that ApplyCommand can call the same
+// function once it exists and the two commands stay consistent.
+func applyPlan(ui cli.Ui, b backend.Backend, op *backend.Operation) int {
+	op.Type = backend.OperationTypeApply
+
+	result, err := b.Operation(context.Background(), op)
+	if err != nil {
+		ui.Error(fmt.Sprintf("Error applying plan: %s", err))
+		return 1
+	}
+
+	add, change, destroy := views.PlanChangeSummary(result.Plan.Diff)
+	ui.Output(fmt.Sprintf(
+		"\nApply complete! Resources: %d added, %d changed, %d destroyed.",
+		add, change, destroy))
+
+	return 0
+}
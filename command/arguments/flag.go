@@ -0,0 +1,44 @@
+package arguments
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FlagStringSlice is a flag.Value implementation for string flags that can
+// be set multiple times on the command line, such as repeated "-target"
+// flags. The resulting value is the list of strings in the order they were
+// given.
+type FlagStringSlice []string
+
+func (v *FlagStringSlice) String() string {
+	return strings.Join(*v, ",")
+}
+
+func (v *FlagStringSlice) Set(raw string) error {
+	*v = append(*v, raw)
+	return nil
+}
+
+// FlagKV is a flag.Value implementation for "key=value" flags that can be
+// set multiple times, such as repeated "-var" flags.
+type FlagKV map[string]string
+
+func (v *FlagKV) String() string {
+	return ""
+}
+
+func (v *FlagKV) Set(raw string) error {
+	idx := strings.Index(raw, "=")
+	if idx == -1 {
+		return fmt.Errorf("no '=' value in arg: %s", raw)
+	}
+
+	if *v == nil {
+		*v = make(map[string]string)
+	}
+
+	key, value := raw[0:idx], raw[idx+1:]
+	(*v)[key] = value
+	return nil
+}
@@ -0,0 +1,158 @@
+package arguments
+
+import (
+	"flag"
+	"fmt"
+	"time"
+)
+
+// ViewType selects which views.Plan implementation the plan command should
+// render its output through.
+type ViewType rune
+
+const (
+	// ViewHuman is the default view, producing the colorized diff output
+	// that "terraform plan" has always printed.
+	ViewHuman ViewType = 'H'
+
+	// ViewJSON selects the machine-readable view, which streams one JSON
+	// object per line.
+	ViewJSON ViewType = 'J'
+)
+
+// planDefaultStateFilename mirrors command.DefaultStateFilename. It is
+// duplicated here, rather than imported, to avoid a dependency cycle between
+// the command and arguments packages.
+const planDefaultStateFilename = "terraform.tfstate"
+
+// Plan represents the command-line arguments for the "terraform plan"
+// command, parsed out of the raw argument list so that PlanCommand.Run
+// doesn't need to do its own flag handling.
+type Plan struct {
+	Destroy   bool
+	Refresh   bool
+	OutPath   string
+	StatePath string
+	Path      string
+	ViewType  ViewType
+
+	// Input set to false marks the run as non-interactive, which
+	// PlanCommand's automation detection honors. Nothing in the plan path
+	// prompts for variable values, whatever its value.
+	Input bool
+
+	// Lock and LockTimeout control state locking around the plan's
+	// refresh. LockTimeout is only meaningful when Lock is true.
+	Lock        bool
+	LockTimeout time.Duration
+
+	// Parallelism caps the number of concurrent resource operations.
+	Parallelism int
+
+	// Targets restricts planning to the given resource addresses, in the
+	// format accepted by addrs.ParseTargetStr.
+	Targets []string
+
+	// Vars holds the "-var" values; a later "-var" for the same name
+	// overrides an earlier one. VarFiles holds the "-var-file" paths, which
+	// ParsePlan rejects with an error whenever any are given.
+	Vars     map[string]string
+	VarFiles []string
+
+	// DetailedExitcode, when set, causes PlanCommand.Run to return 2
+	// instead of 0 when the plan contains changes, so that automation can
+	// distinguish "no changes" from "changes pending" without parsing
+	// output.
+	DetailedExitcode bool
+
+	// AutoApply, when set and PlanCommand is running interactively, causes
+	// PlanCommand to prompt the user to apply the plan it just produced,
+	// without requiring a round-trip through an "-out" file.
+	AutoApply bool
+
+	// AllowDeferral records "-allow-deferral" and is passed on as
+	// backend.Operation.AllowDeferral. No backend in this tree reads it, so
+	// it does not change the plan: imports whose "id" expression is unknown
+	// still fail it.
+	AllowDeferral bool
+}
+
+// ParsePlan processes the given command-line arguments and returns a Plan
+// value ready to drive PlanCommand.Run, along with any errors encountered
+// while parsing.
+func ParsePlan(args []string) (*Plan, []error) {
+	plan := &Plan{
+		Refresh:     true,
+		StatePath:   planDefaultStateFilename,
+		Input:       true,
+		Lock:        true,
+		LockTimeout: 0,
+		Parallelism: 10,
+	}
+
+	var jsonOutput bool
+	var targets FlagStringSlice
+	var vars FlagKV
+	var varFiles FlagStringSlice
+
+	cmdFlags := flag.NewFlagSet("plan", flag.ContinueOnError)
+	cmdFlags.BoolVar(&plan.Destroy, "destroy", false, "destroy")
+	cmdFlags.BoolVar(&plan.Refresh, "refresh", true, "refresh")
+	cmdFlags.StringVar(&plan.OutPath, "out", "", "path")
+	cmdFlags.StringVar(&plan.StatePath, "state", planDefaultStateFilename, "path")
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "produce machine-readable JSON output")
+	cmdFlags.BoolVar(&plan.Input, "input", true, "ask for input for variables if not directly set")
+	cmdFlags.BoolVar(&plan.Lock, "lock", true, "lock the state file when locking is supported")
+	cmdFlags.DurationVar(&plan.LockTimeout, "lock-timeout", 0, "duration to retry a state lock")
+	cmdFlags.IntVar(&plan.Parallelism, "parallelism", 10, "limit the number of concurrent operations")
+	cmdFlags.Var(&targets, "target", "resource to target")
+	cmdFlags.Var(&vars, "var", "variable in the form key=value")
+	cmdFlags.Var(&varFiles, "var-file", "path to a variables file")
+	cmdFlags.BoolVar(&plan.DetailedExitcode, "detailed-exitcode", false, "return a detailed exit code")
+	cmdFlags.BoolVar(&plan.AutoApply, "auto-apply", false, "prompt to apply the plan immediately after it is shown")
+	cmdFlags.BoolVar(&plan.AllowDeferral, "allow-deferral", false, "defer imports whose id cannot yet be resolved instead of failing the plan")
+	cmdFlags.Usage = func() {}
+
+	var errs []error
+	if err := cmdFlags.Parse(args); err != nil {
+		errs = append(errs, err)
+		return plan, errs
+	}
+
+	rest := cmdFlags.Args()
+	switch {
+	case len(rest) > 1:
+		errs = append(errs, fmt.Errorf(
+			"the plan command expects at most one argument with the path\n"+
+				"to a Terraform configuration"))
+	case len(rest) == 1:
+		plan.Path = rest[0]
+	}
+
+	plan.Targets = []string(targets)
+	plan.Vars = map[string]string(vars)
+	plan.VarFiles = []string(varFiles)
+
+	if len(plan.VarFiles) > 0 {
+		// Loading and merging *.tfvars content isn't implemented yet, so
+		// refuse the flag outright rather than silently ignoring the file.
+		errs = append(errs, fmt.Errorf(
+			"-var-file is not yet supported; pass variable values with -var instead"))
+	}
+
+	plan.ViewType = ViewHuman
+	if jsonOutput {
+		plan.ViewType = ViewJSON
+	}
+
+	if plan.AutoApply && plan.ViewType == ViewJSON {
+		// The "-auto-apply" confirmation prompt and its "Apply cancelled."
+		// response are human-readable lines with no "type" field; mixing
+		// them into a "-json" stream would give consumers output they
+		// can't parse as the newline-delimited JSON they were promised.
+		errs = append(errs, fmt.Errorf(
+			"-auto-apply cannot be combined with -json"))
+	}
+
+	return plan, errs
+}
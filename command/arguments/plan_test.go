@@ -0,0 +1,78 @@
+package arguments
+
+import "testing"
+
+func TestParsePlan_Defaults(t *testing.T) {
+	p, errs := ParsePlan(nil)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if !p.Refresh || !p.Input || !p.Lock {
+		t.Fatalf("expected refresh/input/lock to default true, got %#v", p)
+	}
+	if p.Parallelism != 10 {
+		t.Fatalf("expected default parallelism 10, got %d", p.Parallelism)
+	}
+	if p.ViewType != ViewHuman {
+		t.Fatalf("expected default view to be human, got %v", p.ViewType)
+	}
+}
+
+func TestParsePlan_RepeatedFlags(t *testing.T) {
+	p, errs := ParsePlan([]string{
+		"-target=aws_instance.a",
+		"-target=aws_instance.b",
+		"-var", "foo=bar",
+		"-var", "baz=qux",
+	})
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(p.Targets) != 2 || p.Targets[0] != "aws_instance.a" || p.Targets[1] != "aws_instance.b" {
+		t.Fatalf("unexpected targets: %#v", p.Targets)
+	}
+	if p.Vars["foo"] != "bar" || p.Vars["baz"] != "qux" {
+		t.Fatalf("unexpected vars: %#v", p.Vars)
+	}
+}
+
+func TestParsePlan_VarFileRejected(t *testing.T) {
+	_, errs := ParsePlan([]string{"-var-file=testdata.tfvars"})
+	if len(errs) == 0 {
+		t.Fatal("expected -var-file to be rejected until *.tfvars loading is implemented")
+	}
+}
+
+func TestParsePlan_JSON(t *testing.T) {
+	p, errs := ParsePlan([]string{"-json"})
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if p.ViewType != ViewJSON {
+		t.Fatalf("expected JSON view type, got %v", p.ViewType)
+	}
+}
+
+func TestParsePlan_TooManyArgs(t *testing.T) {
+	_, errs := ParsePlan([]string{"one", "two"})
+	if len(errs) == 0 {
+		t.Fatal("expected an error for more than one positional argument")
+	}
+}
+
+func TestParsePlan_AllowDeferral(t *testing.T) {
+	p, errs := ParsePlan([]string{"-allow-deferral"})
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if !p.AllowDeferral {
+		t.Fatal("expected -allow-deferral to set AllowDeferral")
+	}
+}
+
+func TestParsePlan_AutoApplyRejectedWithJSON(t *testing.T) {
+	_, errs := ParsePlan([]string{"-auto-apply", "-json"})
+	if len(errs) == 0 {
+		t.Fatal("expected -auto-apply combined with -json to be rejected")
+	}
+}
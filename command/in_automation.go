@@ -0,0 +1,29 @@
+package command
+
+import "os"
+
+// automationEnvVar is set by CI/CD systems and other non-interactive
+// callers to tell Terraform it isn't talking to a human.
+const automationEnvVar = "TF_IN_AUTOMATION"
+
+// inAutomation reports whether Terraform appears to be running inside an
+// automation pipeline rather than being driven interactively: the
+// TF_IN_AUTOMATION environment variable is set, the caller explicitly
+// passed "-input=false", or stdout isn't a terminal.
+func inAutomation(input bool) bool {
+	if os.Getenv(automationEnvVar) != "" {
+		return true
+	}
+	if !input {
+		return true
+	}
+	return !isTerminal(os.Stdout)
+}
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,61 @@
+package command
+
+import (
+	"os"
+	"testing"
+)
+
+func TestInAutomation_EnvVar(t *testing.T) {
+	os.Setenv(automationEnvVar, "1")
+	defer os.Unsetenv(automationEnvVar)
+
+	if !inAutomation(true) {
+		t.Fatalf("expected inAutomation to be true when %s is set", automationEnvVar)
+	}
+}
+
+func TestInAutomation_InputFalse(t *testing.T) {
+	os.Unsetenv(automationEnvVar)
+
+	if !inAutomation(false) {
+		t.Fatalf("expected inAutomation to be true when input is false")
+	}
+}
+
+func TestInAutomation_InteractiveDefault(t *testing.T) {
+	os.Unsetenv(automationEnvVar)
+
+	// A pipe is never a terminal, so with the env var unset and input
+	// true, detection falls back to isTerminal(os.Stdout) and should
+	// report automation in this test process.
+	if !inAutomation(true) {
+		t.Fatalf("expected inAutomation to be true when stdout isn't a terminal")
+	}
+}
+
+func TestIsTerminal_Pipe(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("error creating pipe: %s", err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	if isTerminal(w) {
+		t.Fatalf("expected a pipe to not be reported as a terminal")
+	}
+}
+
+func TestIsTerminal_ClosedFile(t *testing.T) {
+	f, err := os.CreateTemp("", "in_automation_test")
+	if err != nil {
+		t.Fatalf("error creating temp file: %s", err)
+	}
+	name := f.Name()
+	f.Close()
+	defer os.Remove(name)
+
+	if isTerminal(f) {
+		t.Fatalf("expected Stat on a closed file to fail and report not-a-terminal")
+	}
+}
@@ -1,8 +1,7 @@
 package command
 
 import (
-	"flag"
-	"fmt"
+	"context"
 	"log"
 	"os"
 	"strings"
@@ -10,6 +9,11 @@ import (
 	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/command/arguments"
+	"github.com/opentofu/opentofu/command/views"
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/backend/local"
 )
 
 // PlanCommand is a Command implementation that compares a Terraform
@@ -17,40 +21,37 @@ import (
 type PlanCommand struct {
 	ContextOpts *terraform.ContextOpts
 	Ui          cli.Ui
+
+	// Backend is the backend.Backend used to run the plan and, for
+	// "-auto-apply", the apply that follows it. It defaults to a local
+	// backend when nil, which is always the case outside of tests: this
+	// field exists so tests can substitute a fake Backend instead of
+	// exercising a real terraform.Context.
+	Backend backend.Backend
 }
 
-func (c *PlanCommand) Run(args []string) int {
-	var destroy, refresh bool
-	var outPath, statePath string
-
-	cmdFlags := flag.NewFlagSet("plan", flag.ContinueOnError)
-	cmdFlags.BoolVar(&destroy, "destroy", false, "destroy")
-	cmdFlags.BoolVar(&refresh, "refresh", true, "refresh")
-	cmdFlags.StringVar(&outPath, "out", "", "path")
-	cmdFlags.StringVar(&statePath, "state", DefaultStateFilename, "path")
-	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
-	if err := cmdFlags.Parse(args); err != nil {
+func (c *PlanCommand) Run(rawArgs []string) int {
+	args, argErrs := arguments.ParsePlan(rawArgs)
+	view := views.NewPlan(args.ViewType, c.Ui)
+	if len(argErrs) > 0 {
+		for _, err := range argErrs {
+			view.Diagnostics("error", err.Error(), "")
+		}
 		return 1
 	}
 
-	var path string
-	args = cmdFlags.Args()
-	if len(args) > 1 {
-		c.Ui.Error(
-			"The plan command expects at most one argument with the path\n" +
-				"to a Terraform configuration.\n")
-		cmdFlags.Usage()
-		return 1
-	} else if len(args) == 1 {
-		path = args[0]
-	} else {
+	path := args.Path
+	if path == "" {
 		var err error
 		path, err = os.Getwd()
 		if err != nil {
-			c.Ui.Error(fmt.Sprintf("Error getting pwd: %s", err))
+			view.Diagnostics("error", "Error getting pwd", err.Error())
+			return 1
 		}
 	}
 
+	statePath := args.StatePath
+
 	// If the default state path doesn't exist, ignore it.
 	if statePath != "" {
 		if _, err := os.Stat(statePath); err != nil {
@@ -60,84 +61,88 @@ func (c *PlanCommand) Run(args []string) int {
 		}
 	}
 
-	// Load up the state
-	var state *terraform.State
-	if statePath != "" {
-		f, err := os.Open(statePath)
-		if err != nil {
-			c.Ui.Error(fmt.Sprintf("Error loading state: %s", err))
-			return 1
-		}
-
-		state, err = terraform.ReadState(f)
-		f.Close()
-		if err != nil {
-			c.Ui.Error(fmt.Sprintf("Error loading state: %s", err))
-			return 1
-		}
-	}
-
 	b, err := config.LoadDir(path)
 	if err != nil {
-		c.Ui.Error(fmt.Sprintf("Error loading config: %s", err))
+		view.Diagnostics("error", "Error loading config", err.Error())
 		return 1
 	}
 
 	c.ContextOpts.Config = b
-	c.ContextOpts.Hooks = append(c.ContextOpts.Hooks, &UiHook{Ui: c.Ui})
-	c.ContextOpts.State = state
-	ctx := terraform.NewContext(c.ContextOpts)
-	if !validateContext(ctx, c.Ui) {
-		return 1
+	c.ContextOpts.Hooks = append(c.ContextOpts.Hooks, &UiHook{
+		Ui:   c.Ui,
+		JSON: args.ViewType == arguments.ViewJSON,
+	})
+	if args.Refresh {
+		view.RefreshStart()
 	}
 
-	if refresh {
-		c.Ui.Output("Refreshing Terraform state prior to plan...\n")
-		if _, err := ctx.Refresh(); err != nil {
-			c.Ui.Error(fmt.Sprintf("Error refreshing state: %s", err))
-			return 1
-		}
-		c.Ui.Output("")
+	be := c.Backend
+	if be == nil {
+		be = local.New()
+	}
+	op := &backend.Operation{
+		Type:          backend.OperationTypePlan,
+		ContextOpts:   c.ContextOpts,
+		ConfigDir:     path,
+		StatePath:     statePath,
+		Destroy:       args.Destroy,
+		Refresh:       args.Refresh,
+		Parallelism:   args.Parallelism,
+		Targets:       args.Targets,
+		Vars:          args.Vars,
+		VarFiles:      args.VarFiles,
+		Input:         args.Input,
+		Lock:          args.Lock,
+		LockTimeout:   args.LockTimeout,
+		AllowDeferral: args.AllowDeferral,
 	}
 
-	plan, err := ctx.Plan(&terraform.PlanOpts{Destroy: destroy})
+	result, err := be.Operation(context.Background(), op)
 	if err != nil {
-		c.Ui.Error(fmt.Sprintf("Error running plan: %s", err))
+		view.Diagnostics("error", "Error running plan", err.Error())
 		return 1
 	}
+	plan := result.Plan
 
 	if plan.Diff.Empty() {
-		c.Ui.Output(
-			"No changes. Infrastructure is up-to-date. This means that Terraform\n" +
-				"could not detect any differences between your configuration and\n" +
-				"the real physical resources that exist. As a result, Terraform\n" +
-				"doesn't need to do anything.")
+		view.NoChanges()
 		return 0
 	}
 
-	if outPath != "" {
-		log.Printf("[INFO] Writing plan output to: %s", outPath)
-		f, err := os.Create(outPath)
+	if args.OutPath != "" {
+		log.Printf("[INFO] Writing plan output to: %s", args.OutPath)
+		f, err := os.Create(args.OutPath)
 		if err == nil {
 			defer f.Close()
 			err = terraform.WritePlan(plan, f)
 		}
 		if err != nil {
-			c.Ui.Error(fmt.Sprintf("Error writing plan file: %s", err))
+			view.Diagnostics("error", "Error writing plan file", err.Error())
 			return 1
 		}
 	}
 
-	if outPath == "" {
-		c.Ui.Output(strings.TrimSpace(planHeaderNoOutput) + "\n")
-	} else {
-		c.Ui.Output(fmt.Sprintf(
-			strings.TrimSpace(planHeaderYesOutput)+"\n",
-			outPath))
-	}
+	automation := inAutomation(args.Input)
+	view.Plan(plan, args.OutPath, automation)
+
+	if !automation && args.AutoApply {
+		confirmed, err := c.Ui.Ask("\nApply this plan? Only 'yes' will be accepted to approve.")
+		if err != nil {
+			view.Diagnostics("error", "Error asking for confirmation", err.Error())
+			return 1
+		}
+
+		if strings.TrimSpace(confirmed) == "yes" {
+			op.Plan = plan
+			return applyPlan(c.Ui, be, op)
+		}
 
-	c.Ui.Output(FormatPlan(plan, nil))
+		c.Ui.Output("Apply cancelled.")
+	}
 
+	if args.DetailedExitcode {
+		return 2
+	}
 	return 0
 }
 
@@ -154,18 +159,58 @@ Usage: terraform plan [options] [dir]
 
 Options:
 
+  -allow-deferral     Accepted, but has no effect on the plan: imports whose
+                      "id" expression can't be resolved still fail it.
+
+  -auto-apply         When running interactively, prompt to apply the plan
+                      immediately after it is shown, without writing it to
+                      an "-out" file first. Ignored in automation (see
+                      TF_IN_AUTOMATION below). Cannot be combined with
+                      "-json".
+
   -destroy            If set, a plan will be generated to destroy all resources
                       managed by the given configuration and state.
 
+  -detailed-exitcode  Return a detailed exit code: 0 for no changes, 1 for
+                      an error, and 2 when the plan contains changes.
+
+  -input=true         With "-input=false", plan runs as in automation (see
+                      below). Plan never prompts for variable values.
+
+  -json               Produce machine-readable JSON output, including
+                      diagnostics, on stdout, one object per line.
+
+  -lock=true          Lock the state file when locking is supported.
+
+  -lock-timeout=0s    Duration to retry a state lock.
+
   -out=path           Write a plan file to the given path. This can be used as
                       input to the "apply" command.
 
+  -parallelism=n      Limit the number of concurrent resource operations.
+                      Defaults to 10.
+
   -refresh=true       Update state prior to checking for differences.
 
   -state=statefile    Path to a Terraform state file to use to look
                       up Terraform-managed resources. By default it will
                       use the state "terraform.tfstate" if it exists.
 
+  -target=resource    A resource address to target. Can be used multiple
+                      times.
+
+  -var 'foo=bar'      Set a variable in the Terraform configuration. Can be
+                      used multiple times.
+
+  -var-file=foo       Not yet supported; rejected with an error. Pass
+                      variable values with "-var" instead.
+
+If the TF_IN_AUTOMATION environment variable is set, stdout isn't a
+terminal, or "-input=false" is given, plan assumes it's being driven by a
+script rather than a human: the "-out" reminder is suppressed and a
+"Plan: N to add, M to change, K to destroy." summary line is printed
+instead, for the caller to parse. With "-json" no summary line is printed.
+
 `
 	return strings.TrimSpace(helpText)
 }
@@ -173,28 +218,3 @@ Options:
 func (c *PlanCommand) Synopsis() string {
 	return "Generate and show an execution plan"
 }
-
-const planHeaderNoOutput = `
-The Terraform execution plan has been generated and is shown below.
-Resources are shown in alphabetical order for quick scanning. Green resources
-will be created (or destroyed and then created if an existing resource
-exists), yellow resources are being changed in-place, and red resources
-will be destroyed.
-
-Note: You didn't specify an "-out" parameter to save this plan, so when
-"apply" is called, Terraform can't guarantee this is what will execute.
-`
-
-const planHeaderYesOutput = `
-The Terraform execution plan has been generated and is shown below.
-Resources are shown in alphabetical order for quick scanning. Green resources
-will be created (or destroyed and then created if an existing resource
-exists), yellow resources are being changed in-place, and red resources
-will be destroyed.
-
-Your plan was also saved to the path below. Call the "apply" subcommand
-with this plan file and Terraform will exactly execute this execution
-plan.
-
-Path: %s
-`
@@ -0,0 +1,142 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/backend"
+)
+
+// fakeBackend is a backend.Backend stand-in so these tests can drive
+// PlanCommand.Run without a real terraform.Context or state file. Run still
+// loads the configuration directory itself, so tests pass it emptyConfigDir.
+type fakeBackend struct {
+	planResult *backend.Result
+	planErr    error
+
+	applyResult *backend.Result
+	applyErr    error
+
+	calls []backend.OperationType
+}
+
+func (f *fakeBackend) Operation(ctx context.Context, op *backend.Operation) (*backend.Result, error) {
+	f.calls = append(f.calls, op.Type)
+	if op.Type == backend.OperationTypeApply {
+		return f.applyResult, f.applyErr
+	}
+	return f.planResult, f.planErr
+}
+
+func emptyDiffPlan() *terraform.Plan {
+	return &terraform.Plan{Diff: &terraform.Diff{}}
+}
+
+// emptyConfigDir returns a temporary directory holding one empty
+// configuration file, since config.LoadDir rejects a directory with none.
+func emptyConfigDir(t *testing.T) string {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.tf"), nil, 0644); err != nil {
+		t.Fatalf("error writing configuration file: %s", err)
+	}
+	return dir
+}
+
+func TestPlanCommand_NoChanges(t *testing.T) {
+	ui := cli.NewMockUi()
+	be := &fakeBackend{planResult: &backend.Result{Plan: emptyDiffPlan()}}
+	c := &PlanCommand{
+		ContextOpts: &terraform.ContextOpts{},
+		Ui:          ui,
+		Backend:     be,
+	}
+
+	got := c.Run([]string{"-input=false", emptyConfigDir(t)})
+	if got != 0 {
+		t.Fatalf("expected exit 0, got %d (stderr: %s)", got, ui.ErrorWriter.String())
+	}
+	if !strings.Contains(ui.OutputWriter.String(), "No changes.") {
+		t.Fatalf("expected a no-changes message, got %q", ui.OutputWriter.String())
+	}
+	if len(be.calls) != 1 || be.calls[0] != backend.OperationTypePlan {
+		t.Fatalf("expected exactly one plan operation, got %#v", be.calls)
+	}
+}
+
+func TestPlanCommand_BackendError(t *testing.T) {
+	ui := cli.NewMockUi()
+	be := &fakeBackend{planErr: errors.New("state is locked")}
+	c := &PlanCommand{
+		ContextOpts: &terraform.ContextOpts{},
+		Ui:          ui,
+		Backend:     be,
+	}
+
+	got := c.Run([]string{"-input=false", emptyConfigDir(t)})
+	if got != 1 {
+		t.Fatalf("expected exit 1, got %d", got)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "state is locked") {
+		t.Fatalf("expected the backend error to surface, got %q", ui.ErrorWriter.String())
+	}
+}
+
+func TestPlanCommand_ArgErrorRejectsAutoApplyWithJSON(t *testing.T) {
+	ui := cli.NewMockUi()
+	be := &fakeBackend{}
+	c := &PlanCommand{
+		ContextOpts: &terraform.ContextOpts{},
+		Ui:          ui,
+		Backend:     be,
+	}
+
+	got := c.Run([]string{"-auto-apply", "-json", t.TempDir()})
+	if got != 1 {
+		t.Fatalf("expected exit 1 for -auto-apply combined with -json, got %d", got)
+	}
+	if len(be.calls) != 0 {
+		t.Fatalf("expected the backend never to be called when argument parsing fails, got %#v", be.calls)
+	}
+}
+
+// TestPlanCommand_AutoApplyIgnoredInAutomation exercises the "-auto-apply"
+// path's automation guard: -input=false forces automation mode, in which
+// "-auto-apply" is never acted on, so the command returns as soon as the
+// plan is shown without ever asking for confirmation or calling apply.
+//
+// The interactive confirm/cancel branch itself isn't covered here: it's
+// only reachable when stdout is a terminal, which a test process's stdout
+// never is, so driving it end-to-end would require faking terminal
+// detection rather than testing PlanCommand.Run as written. inAutomation's
+// own TTY/env-var logic is covered directly in in_automation_test.go.
+func TestPlanCommand_AutoApplyIgnoredInAutomation(t *testing.T) {
+	ui := cli.NewMockUi()
+	plan := &terraform.Plan{
+		Diff: &terraform.Diff{
+			Modules: []*terraform.ModuleDiff{
+				{Resources: map[string]*terraform.InstanceDiff{"aws_instance.foo": {Destroy: true}}},
+			},
+		},
+	}
+	be := &fakeBackend{planResult: &backend.Result{Plan: plan}}
+	c := &PlanCommand{
+		ContextOpts: &terraform.ContextOpts{},
+		Ui:          ui,
+		Backend:     be,
+	}
+
+	got := c.Run([]string{"-input=false", "-auto-apply", emptyConfigDir(t)})
+	if got != 0 {
+		t.Fatalf("expected exit 0, got %d (stderr: %s)", got, ui.ErrorWriter.String())
+	}
+	if len(be.calls) != 1 || be.calls[0] != backend.OperationTypePlan {
+		t.Fatalf("expected apply never to run in automation mode, got %#v", be.calls)
+	}
+}
@@ -0,0 +1,48 @@
+package command
+
+import (
+	"encoding/json"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+)
+
+// UiHook implements terraform.Hook to write output to the CLI's Ui object
+// as Terraform progresses through its operations.
+//
+// When JSON is true, the hook emits structured events in place of the
+// free-form messages it otherwise prints, so that callers consuming the
+// "-json" plan output see a single, uniform event stream. Its events use
+// their own "type" values, distinct from views.PlanJSON's command-level
+// events: "resource_refresh_start" ({"address": string}) fires once per
+// resource as it's refreshed, whereas views.PlanJSON's "refresh_start" fires
+// once for the whole refresh phase.
+type UiHook struct {
+	terraform.NilHook
+
+	Ui   cli.Ui
+	JSON bool
+}
+
+func (h *UiHook) PreRefresh(n *terraform.InstanceInfo, s *terraform.InstanceState) (terraform.HookAction, error) {
+	if h.JSON {
+		h.outputJSON(map[string]interface{}{
+			"type":    "resource_refresh_start",
+			"address": n.HumanId(),
+		})
+	} else {
+		h.Ui.Output(n.HumanId() + ": Refreshing state...")
+	}
+
+	return terraform.HookActionContinue, nil
+}
+
+func (h *UiHook) outputJSON(obj map[string]interface{}) {
+	line, err := json.Marshal(obj)
+	if err != nil {
+		// obj is always built from plain data above, so this can't fail.
+		panic(err)
+	}
+
+	h.Ui.Output(string(line))
+}
@@ -0,0 +1,201 @@
+package views
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/command/arguments"
+)
+
+// Plan is the view abstraction for the "terraform plan" command. It is
+// responsible for all output the command produces, so that PlanCommand.Run
+// can stay focused on orchestrating the plan itself.
+type Plan interface {
+	// RefreshStart is called just before Terraform refreshes state, if
+	// refreshing was requested.
+	RefreshStart()
+
+	// NoChanges is called when the plan contains no differences.
+	NoChanges()
+
+	// Plan is called with the completed plan, and the path it was written
+	// to, if any. In PlanHuman, when automation is true, the "-out" reminder
+	// is suppressed and a machine-parseable summary line is printed instead,
+	// for consumption by scripts rather than a human at a terminal. PlanJSON
+	// ignores automation.
+	Plan(plan *terraform.Plan, outPath string, automation bool)
+
+	// Diagnostics renders a single error or warning message.
+	Diagnostics(severity, summary, detail string)
+}
+
+// NewPlan returns the Plan view implementation appropriate for the given
+// view type.
+func NewPlan(viewType arguments.ViewType, ui cli.Ui) Plan {
+	switch viewType {
+	case arguments.ViewJSON:
+		return &PlanJSON{ui: ui}
+	default:
+		return &PlanHuman{ui: ui}
+	}
+}
+
+// PlanHuman is the default Plan view, producing the same colorized diff
+// output "terraform plan" has always printed.
+type PlanHuman struct {
+	ui cli.Ui
+}
+
+func (v *PlanHuman) RefreshStart() {
+	v.ui.Output("Refreshing Terraform state prior to plan...\n")
+}
+
+func (v *PlanHuman) NoChanges() {
+	v.ui.Output(
+		"No changes. Infrastructure is up-to-date. This means that Terraform\n" +
+			"could not detect any differences between your configuration and\n" +
+			"the real physical resources that exist. As a result, Terraform\n" +
+			"doesn't need to do anything.")
+}
+
+func (v *PlanHuman) Plan(plan *terraform.Plan, outPath string, automation bool) {
+	switch {
+	case outPath != "":
+		v.ui.Output(fmt.Sprintf(
+			strings.TrimSpace(planHeaderYesOutput)+"\n",
+			outPath))
+	case !automation:
+		v.ui.Output(strings.TrimSpace(planHeaderNoOutput) + "\n")
+	}
+
+	v.ui.Output(FormatPlan(plan, nil))
+
+	if automation {
+		add, change, destroy := PlanChangeSummary(plan.Diff)
+		v.ui.Output(fmt.Sprintf(
+			"\nPlan: %d to add, %d to change, %d to destroy.",
+			add, change, destroy))
+	}
+}
+
+func (v *PlanHuman) Diagnostics(severity, summary, detail string) {
+	msg := summary
+	if detail != "" {
+		msg = fmt.Sprintf("%s: %s", summary, detail)
+	}
+	if severity == "error" {
+		v.ui.Error(msg)
+	} else {
+		v.ui.Warn(msg)
+	}
+}
+
+// PlanChangeSummary tallies the resource actions in diff into add, change
+// and destroy counts, for the "Plan: N to add, M to change, K to destroy."
+// summary line.
+func PlanChangeSummary(diff *terraform.Diff) (add, change, destroy int) {
+	for _, m := range diff.Modules {
+		for _, r := range m.Resources {
+			switch r.ChangeType() {
+			case terraform.DiffCreate:
+				add++
+			case terraform.DiffDestroyCreate:
+				add++
+				destroy++
+			case terraform.DiffDestroy:
+				destroy++
+			case terraform.DiffUpdate:
+				change++
+			}
+		}
+	}
+	return add, change, destroy
+}
+
+// PlanJSON is the machine-readable Plan view, streaming one JSON object per
+// line. Every line has a "type" field identifying its shape:
+//
+//   - "refresh_start": {} — the refresh phase of the plan is starting.
+//     Carries no other fields.
+//   - "no_changes": {} — the plan found no differences to apply. Carries
+//     no other fields.
+//   - "planned_change": {"address": string} — one resource instance the
+//     plan would change.
+//   - "outputs": {"out_path": string} — the plan finished; out_path is ""
+//     unless "-out" was given.
+//   - "diagnostic": {"severity", "summary", "detail": string} — an error
+//     or warning.
+type PlanJSON struct {
+	ui cli.Ui
+}
+
+func (v *PlanJSON) emit(obj map[string]interface{}) {
+	line, err := json.Marshal(obj)
+	if err != nil {
+		// obj is always constructed by this file from plain data, so
+		// marshaling can't realistically fail.
+		panic(err)
+	}
+	v.ui.Output(string(line))
+}
+
+func (v *PlanJSON) RefreshStart() {
+	v.emit(map[string]interface{}{"type": "refresh_start"})
+}
+
+func (v *PlanJSON) NoChanges() {
+	v.emit(map[string]interface{}{"type": "no_changes"})
+}
+
+func (v *PlanJSON) Plan(plan *terraform.Plan, outPath string, automation bool) {
+	for _, m := range plan.Diff.Modules {
+		for addr := range m.Resources {
+			v.emit(map[string]interface{}{
+				"type":    "planned_change",
+				"address": addr,
+			})
+		}
+	}
+	v.emit(map[string]interface{}{
+		"type":     "outputs",
+		"out_path": outPath,
+	})
+}
+
+func (v *PlanJSON) Diagnostics(severity, summary, detail string) {
+	v.emit(map[string]interface{}{
+		"type":     "diagnostic",
+		"severity": severity,
+		"summary":  summary,
+		"detail":   detail,
+	})
+}
+
+const planHeaderNoOutput = `
+The Terraform execution plan has been generated and is shown below.
+Resources are shown in alphabetical order for quick scanning. Green resources
+will be created (or destroyed and then created if an existing resource
+exists), yellow resources are being changed in-place, and red resources
+will be destroyed.
+
+Note: You didn't specify an "-out" parameter to save this plan, so when
+"apply" is called, Terraform can't guarantee this is what will execute.
+`
+
+const planHeaderYesOutput = `
+The Terraform execution plan has been generated and is shown below.
+Resources are shown in alphabetical order for quick scanning. Green resources
+will be created (or destroyed and then created if an existing resource
+exists), yellow resources are being changed in-place, and red resources
+will be destroyed.
+
+Your plan was also saved to the path below. Call the "apply" subcommand
+with this plan file and Terraform will exactly execute this execution
+plan.
+
+Path: %s
+`
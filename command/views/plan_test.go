@@ -0,0 +1,166 @@
+package views
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+)
+
+func decodeLines(t *testing.T, raw string) []map[string]interface{} {
+	t.Helper()
+
+	var lines []map[string]interface{}
+	for _, l := range strings.Split(strings.TrimSpace(raw), "\n") {
+		if l == "" {
+			continue
+		}
+		var obj map[string]interface{}
+		if err := json.Unmarshal([]byte(l), &obj); err != nil {
+			t.Fatalf("line %q isn't valid JSON: %s", l, err)
+		}
+		lines = append(lines, obj)
+	}
+	return lines
+}
+
+func TestPlanJSON_RefreshStart(t *testing.T) {
+	ui := cli.NewMockUi()
+	v := &PlanJSON{ui: ui}
+
+	v.RefreshStart()
+
+	lines := decodeLines(t, ui.OutputWriter.String())
+	if len(lines) != 1 || lines[0]["type"] != "refresh_start" {
+		t.Fatalf("unexpected output: %#v", lines)
+	}
+	if len(lines[0]) != 1 {
+		t.Fatalf("expected refresh_start to carry no other fields, got %#v", lines[0])
+	}
+}
+
+func TestPlanJSON_NoChanges(t *testing.T) {
+	ui := cli.NewMockUi()
+	v := &PlanJSON{ui: ui}
+
+	v.NoChanges()
+
+	lines := decodeLines(t, ui.OutputWriter.String())
+	if len(lines) != 1 || lines[0]["type"] != "no_changes" {
+		t.Fatalf("unexpected output: %#v", lines)
+	}
+	if len(lines[0]) != 1 {
+		t.Fatalf("expected no_changes to carry no other fields, got %#v", lines[0])
+	}
+}
+
+func TestPlanJSON_Diagnostics(t *testing.T) {
+	ui := cli.NewMockUi()
+	v := &PlanJSON{ui: ui}
+
+	v.Diagnostics("error", "something broke", "details here")
+
+	lines := decodeLines(t, ui.OutputWriter.String())
+	if len(lines) != 1 {
+		t.Fatalf("expected one line, got %#v", lines)
+	}
+	got := lines[0]
+	if got["type"] != "diagnostic" || got["severity"] != "error" ||
+		got["summary"] != "something broke" || got["detail"] != "details here" {
+		t.Fatalf("unexpected diagnostic event: %#v", got)
+	}
+}
+
+func TestPlanJSON_Plan(t *testing.T) {
+	ui := cli.NewMockUi()
+	v := &PlanJSON{ui: ui}
+
+	plan := &terraform.Plan{
+		Diff: &terraform.Diff{
+			Modules: []*terraform.ModuleDiff{
+				{
+					Resources: map[string]*terraform.InstanceDiff{
+						"aws_instance.foo": {},
+					},
+				},
+			},
+		},
+	}
+
+	v.Plan(plan, "out.tfplan", false)
+
+	lines := decodeLines(t, ui.OutputWriter.String())
+	if len(lines) != 2 {
+		t.Fatalf("expected one planned_change line and one outputs line, got %#v", lines)
+	}
+
+	change := lines[0]
+	if change["type"] != "planned_change" || change["address"] != "aws_instance.foo" {
+		t.Fatalf("unexpected planned_change event: %#v", change)
+	}
+	if _, ok := change["out_path"]; ok {
+		t.Fatalf("planned_change should not carry out_path, got %#v", change)
+	}
+
+	outputs := lines[1]
+	if outputs["type"] != "outputs" || outputs["out_path"] != "out.tfplan" {
+		t.Fatalf("unexpected outputs event: %#v", outputs)
+	}
+}
+
+func TestPlanChangeSummary(t *testing.T) {
+	diff := &terraform.Diff{
+		Modules: []*terraform.ModuleDiff{
+			{
+				Resources: map[string]*terraform.InstanceDiff{
+					"aws_instance.create": {
+						Attributes: map[string]*terraform.ResourceAttrDiff{
+							"ami": {RequiresNew: true},
+						},
+					},
+					"aws_instance.update": {
+						Attributes: map[string]*terraform.ResourceAttrDiff{
+							"tags.Name": {Old: "a", New: "b"},
+						},
+					},
+					"aws_instance.destroy": {
+						Destroy: true,
+					},
+				},
+			},
+		},
+	}
+
+	add, change, destroy := PlanChangeSummary(diff)
+	if add != 1 || change != 1 || destroy != 1 {
+		t.Fatalf("expected 1 add, 1 change, 1 destroy, got %d/%d/%d", add, change, destroy)
+	}
+}
+
+func TestPlanHuman_NoChanges(t *testing.T) {
+	ui := cli.NewMockUi()
+	v := &PlanHuman{ui: ui}
+
+	v.NoChanges()
+
+	if !strings.Contains(ui.OutputWriter.String(), "No changes.") {
+		t.Fatalf("expected a no-changes message, got %q", ui.OutputWriter.String())
+	}
+}
+
+func TestPlanHuman_Diagnostics(t *testing.T) {
+	ui := cli.NewMockUi()
+	v := &PlanHuman{ui: ui}
+
+	v.Diagnostics("error", "summary", "detail")
+	if !strings.Contains(ui.ErrorWriter.String(), "summary: detail") {
+		t.Fatalf("expected error output, got %q", ui.ErrorWriter.String())
+	}
+
+	v.Diagnostics("warning", "just a warning", "")
+	if !strings.Contains(ui.ErrorWriter.String(), "just a warning") {
+		t.Fatalf("expected warning output, got %q", ui.ErrorWriter.String())
+	}
+}
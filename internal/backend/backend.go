@@ -0,0 +1,84 @@
+// Package backend defines the abstraction that commands use to read and
+// write state and to carry out operations such as plan and apply, so that
+// command implementations never touch a state file directly.
+package backend
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// OperationType identifies the kind of work a Backend is being asked to do.
+type OperationType int
+
+const (
+	OperationTypeInvalid OperationType = iota
+	OperationTypePlan
+	OperationTypeApply
+)
+
+// Operation describes a single request to a Backend.
+type Operation struct {
+	Type OperationType
+
+	ContextOpts *terraform.ContextOpts
+
+	// ConfigDir is the path to the configuration to plan or apply.
+	ConfigDir string
+
+	// StatePath is the path to the state backing this operation. Backends
+	// that don't store state on local disk may ignore it.
+	StatePath string
+
+	Destroy     bool
+	Refresh     bool
+	Parallelism int
+
+	// Targets restricts the operation to the given resource addresses.
+	Targets []string
+
+	// Vars and VarFiles supply input variable values.
+	Vars     map[string]string
+	VarFiles []string
+
+	// Input is carried through from "-input" on plan. The local backend
+	// does not read it and never prompts for missing values.
+	Input bool
+
+	// Lock and LockTimeout control state locking. LockTimeout is only
+	// meaningful when Lock is true.
+	Lock        bool
+	LockTimeout time.Duration
+
+	// Plan is the plan to carry out, for an Operation of type
+	// OperationTypeApply. It's ignored for OperationTypePlan, which
+	// produces its own.
+	Plan *terraform.Plan
+
+	// AllowDeferral is carried through from "-allow-deferral" on plan. No
+	// Backend in this tree consults it yet: doing so requires building a
+	// tofu.EvalContext, which is part of the newer internal/tofu graph
+	// evaluator rather than the terraform.Context this package's local
+	// implementation runs today. It's threaded through Operation now so
+	// that wiring can land as its own change without another signature
+	// change here.
+	AllowDeferral bool
+}
+
+// Result is what a Backend hands back once an Operation completes.
+type Result struct {
+	Plan  *terraform.Plan
+	State *terraform.State
+}
+
+// Backend owns state access for an operation, so that callers like
+// PlanCommand never open or lock a state file themselves. The only
+// implementation in this tree today is the local backend in
+// internal/backend/local; a remote backend would satisfy the same
+// interface, but none exists here yet.
+type Backend interface {
+	// Operation executes the given operation and returns its result.
+	Operation(ctx context.Context, op *Operation) (*Result, error)
+}
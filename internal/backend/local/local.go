@@ -0,0 +1,161 @@
+// Package local implements the default backend.Backend: the one used when
+// no remote backend is configured, which reads and writes state directly on
+// local disk.
+package local
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/terraform/state"
+	"github.com/hashicorp/terraform/terraform"
+
+	"github.com/opentofu/opentofu/internal/backend"
+)
+
+// Local is the Backend implementation used when no remote backend is
+// configured.
+type Local struct{}
+
+// New returns a Local backend.
+func New() *Local {
+	return &Local{}
+}
+
+// newLocker is a seam over state.NewLocalLocker so tests can exercise the
+// lock-acquisition-failure path without taking a real file lock.
+var newLocker = state.NewLocalLocker
+
+func (b *Local) Operation(ctx context.Context, op *backend.Operation) (*backend.Result, error) {
+	switch op.Type {
+	case backend.OperationTypeApply:
+		return b.apply(op)
+	default:
+		return b.plan(op)
+	}
+}
+
+func (b *Local) plan(op *backend.Operation) (*backend.Result, error) {
+	unlock, err := b.lock(op)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	tfState, err := b.readState(op.StatePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// op.ContextOpts is owned by the caller and reused for any later
+	// operation on the same plan (such as the "-auto-apply" handoff), so
+	// it's populated in place rather than through a local copy.
+	op.ContextOpts.State = tfState
+	op.ContextOpts.Parallelism = op.Parallelism
+	op.ContextOpts.Variables = mergeVariables(op.Vars)
+
+	tfCtx := terraform.NewContext(op.ContextOpts)
+	if _, es := tfCtx.Validate(); len(es) > 0 {
+		return nil, fmt.Errorf("configuration is invalid: %s", es[0])
+	}
+
+	if op.Refresh {
+		if _, err := tfCtx.Refresh(); err != nil {
+			return nil, fmt.Errorf("error refreshing state: %w", err)
+		}
+	}
+
+	plan, err := tfCtx.Plan(&terraform.PlanOpts{
+		Destroy: op.Destroy,
+		Targets: op.Targets,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error running plan: %w", err)
+	}
+
+	return &backend.Result{Plan: plan, State: tfState}, nil
+}
+
+// apply carries out op.Plan, which the caller must have already produced
+// (typically via a prior call to plan using the same op.ContextOpts). It
+// takes its own state lock rather than relying on the plan's having stayed
+// locked, so it's safe to call some time after the plan that produced
+// op.Plan returned.
+func (b *Local) apply(op *backend.Operation) (*backend.Result, error) {
+	if op.Plan == nil {
+		return nil, fmt.Errorf("cannot apply: no plan was provided")
+	}
+
+	unlock, err := b.lock(op)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	op.ContextOpts.State = op.Plan.State
+	tfCtx := terraform.NewContext(op.ContextOpts)
+
+	newState, err := tfCtx.Apply()
+	if err != nil {
+		return nil, fmt.Errorf("error applying plan: %w", err)
+	}
+
+	return &backend.Result{Plan: op.Plan, State: newState}, nil
+}
+
+// lock acquires the state lock for op, if locking was requested, returning
+// a function that releases it. When locking isn't requested, or op has no
+// StatePath, the returned function is a no-op.
+func (b *Local) lock(op *backend.Operation) (func(), error) {
+	if !op.Lock || op.StatePath == "" {
+		return func() {}, nil
+	}
+
+	locker, err := newLocker(op.StatePath, op.LockTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("error preparing state lock: %w", err)
+	}
+	if err := locker.Lock(); err != nil {
+		return nil, fmt.Errorf("error acquiring the state lock: %w", err)
+	}
+
+	return func() { locker.Unlock() }, nil
+}
+
+// mergeVariables converts the -var values collected on the command line
+// into the map format terraform.ContextOpts expects.
+//
+// op.VarFiles is intentionally not consulted here: arguments.ParsePlan
+// rejects "-var-file" outright until *.tfvars loading is implemented, so by
+// the time an Operation reaches this backend, VarFiles is always empty.
+func mergeVariables(vars map[string]string) map[string]interface{} {
+	result := make(map[string]interface{}, len(vars))
+	for k, v := range vars {
+		result[k] = v
+	}
+	return result
+}
+
+func (b *Local) readState(path string) (*terraform.State, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error loading state: %w", err)
+	}
+	defer f.Close()
+
+	tfState, err := terraform.ReadState(f)
+	if err != nil {
+		return nil, fmt.Errorf("error loading state: %w", err)
+	}
+
+	return tfState, nil
+}
@@ -0,0 +1,68 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/state"
+	"github.com/hashicorp/terraform/terraform"
+
+	"github.com/opentofu/opentofu/internal/backend"
+)
+
+type fakeLocker struct {
+	lockErr error
+}
+
+func (f *fakeLocker) Lock() error   { return f.lockErr }
+func (f *fakeLocker) Unlock() error { return nil }
+
+func TestOperation_LockFailure(t *testing.T) {
+	orig := newLocker
+	defer func() { newLocker = orig }()
+
+	newLocker = func(path string, timeout time.Duration) (state.Locker, error) {
+		return &fakeLocker{lockErr: errors.New("state is locked by another process")}, nil
+	}
+
+	_, err := New().Operation(context.Background(), &backend.Operation{
+		ContextOpts: &terraform.ContextOpts{},
+		StatePath:   "terraform.tfstate",
+		Lock:        true,
+	})
+	if err == nil {
+		t.Fatal("expected an error when the state lock can't be acquired")
+	}
+}
+
+func TestOperation_NoLockWhenDisabled(t *testing.T) {
+	orig := newLocker
+	defer func() { newLocker = orig }()
+
+	called := false
+	newLocker = func(path string, timeout time.Duration) (state.Locker, error) {
+		called = true
+		return &fakeLocker{}, nil
+	}
+
+	// StatePath doesn't exist on disk, which readState treats as no state,
+	// and the ContextOpts are empty; the result of Operation isn't checked,
+	// only that locking is never attempted.
+	_, _ = New().Operation(context.Background(), &backend.Operation{
+		ContextOpts: &terraform.ContextOpts{},
+		StatePath:   "terraform.tfstate",
+		Lock:        false,
+	})
+	if called {
+		t.Fatal("expected newLocker not to be called when Lock is false")
+	}
+}
+
+func TestMergeVariables(t *testing.T) {
+	got := mergeVariables(map[string]string{"foo": "bar"})
+	if got["foo"] != "bar" {
+		t.Fatalf("expected variable foo=bar, got %#v", got)
+	}
+}
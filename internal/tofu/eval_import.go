@@ -11,16 +11,83 @@ import (
 	"github.com/zclconf/go-cty/cty/gocty"
 )
 
-func evaluateImportIdExpression(expr hcl.Expression, ctx EvalContext) (string, tfdiags.Diagnostics) {
-	var diags tfdiags.Diagnostics
+// DeferredImport records an import whose ID expression could not be
+// resolved during planning because it depended on unknown values. It is
+// meant to round-trip through the plan file alongside plan.Diff so that
+// apply can re-evaluate Expr, now that upstream resources have known
+// values, and carry out the import at that point.
+//
+// NOTE: the plan/apply plumbing that serializes, stores and consumes this
+// struct lives outside internal/tofu (in the plan file format and the
+// graph nodes that execute apply) and isn't part of this change; today
+// resolveImportId is the only producer.
+type DeferredImport struct {
+	Addr   addrs.AbsResourceInstance
+	Expr   hcl.Expression
+	Reason string
+}
+
+// deferralAllower is satisfied by an EvalContext that opts into deferring
+// imports with not-yet-known IDs instead of failing the plan outright. It's
+// a separate interface, rather than a new method on EvalContext itself, so
+// that this file doesn't have to own (and risk diverging from) the full
+// EvalContext declaration; a concrete context, such as
+// BuiltinEvalContext, can satisfy it by adding a single AllowDeferral
+// method. Contexts that don't implement it keep today's strict behavior,
+// which is the default either way.
+//
+// "-allow-deferral" on plan is parsed by arguments.ParsePlan and carried as
+// far as backend.Operation.AllowDeferral. Reaching this package from there
+// means constructing a BuiltinEvalContext that implements this interface,
+// which requires the rest of the internal/tofu graph evaluator; the plan
+// path this tree's local backend runs today builds a terraform.Context
+// instead, so that last step isn't wired up yet.
+type deferralAllower interface {
+	// AllowDeferral reports whether "-allow-deferral" was set for this
+	// plan.
+	AllowDeferral() bool
+}
+
+func allowDeferral(ctx EvalContext) bool {
+	da, ok := ctx.(deferralAllower)
+	return ok && da.AllowDeferral()
+}
 
+// resolveImportId evaluates an import block's "id" expression for addr,
+// returning either a ready-to-use import ID or, when the expression isn't
+// yet known and deferral is allowed, a DeferredImport describing why the
+// import must wait until apply.
+func resolveImportId(addr addrs.AbsResourceInstance, expr hcl.Expression, ctx EvalContext) (string, *DeferredImport, tfdiags.Diagnostics) {
+	importId, deferred, diags := evaluateImportIdExpression(expr, ctx)
+	if deferred {
+		return "", &DeferredImport{
+			Addr:   addr,
+			Expr:   expr,
+			Reason: "the import ID depends on resource attributes that cannot be determined until apply",
+		}, diags
+	}
+
+	return importId, nil, diags
+}
+
+// evaluateImportIdExpression evaluates the "id" argument of an import block
+// or command, returning the resulting import ID.
+//
+// If the expression depends on values that are not yet known, the import
+// normally can't proceed and this produces a fatal diagnostic. When
+// allowDeferral(ctx) is true (set by "-allow-deferral" on plan), an unknown
+// ID instead yields deferred=true and no diagnostic, so the caller
+// (resolveImportId) can record the import as a deferred change and retry it
+// during apply once the upstream values are known.
+func evaluateImportIdExpression(expr hcl.Expression, ctx EvalContext) (importId string, deferred bool, diags tfdiags.Diagnostics) {
 	if expr == nil {
-		return "", diags.Append(&hcl.Diagnostic{
+		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  "Invalid import id argument",
 			Detail:   "The import ID cannot be null.",
 			Subject:  nil,
 		})
+		return "", false, diags
 	}
 
 	// The import expression is declared within the root module
@@ -31,16 +98,24 @@ func evaluateImportIdExpression(expr hcl.Expression, ctx EvalContext) (string, t
 	diags = diags.Append(evalDiags)
 
 	if importIdVal.IsNull() {
-		return "", diags.Append(&hcl.Diagnostic{
+		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  "Invalid import id argument",
 			Detail:   "The import ID cannot be null.",
 			Subject:  expr.Range().Ptr(),
 		})
+		return "", false, diags
 	}
 
 	if !importIdVal.IsKnown() {
-		return "", diags.Append(&hcl.Diagnostic{
+		if allowDeferral(ctx) {
+			// The caller is responsible for recording this as a deferred
+			// change, carrying the import's address, this expression, and
+			// the fact that it was deferred because of an unknown value.
+			return "", true, diags
+		}
+
+		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  "Invalid import id argument",
 			Detail:   `The import block "id" argument depends on resource attributes that cannot be determined until apply, so OpenTofu cannot plan to import this resource.`, // FIXME and what should I do about that?
@@ -49,27 +124,29 @@ func evaluateImportIdExpression(expr hcl.Expression, ctx EvalContext) (string, t
 			//	EvalContext:
 			Extra: diagnosticCausedByUnknown(true),
 		})
+		return "", false, diags
 	}
 
 	if importIdVal.HasMark(marks.Sensitive) {
-		return "", diags.Append(&hcl.Diagnostic{
+		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  "Invalid import id argument",
 			Detail:   "The import ID cannot be sensitive.",
 			Subject:  expr.Range().Ptr(),
 		})
+		return "", false, diags
 	}
 
-	var importId string
 	err := gocty.FromCtyValue(importIdVal, &importId)
 	if err != nil {
-		return "", diags.Append(&hcl.Diagnostic{
+		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  "Invalid import id argument",
 			Detail:   fmt.Sprintf("The import ID value is unsuitable: %s.", err),
 			Subject:  expr.Range().Ptr(),
 		})
+		return "", false, diags
 	}
 
-	return importId, diags
+	return importId, false, diags
 }